@@ -0,0 +1,98 @@
+package main
+
+import "hash/fnv"
+
+// defaultBloomBits is used when --dedup bloom is selected without an
+// explicit --dedup-size.
+const defaultBloomBits = 10_000_000
+
+// bloomHashCount is the number of bit positions set per item. Four is a
+// reasonable default for the false-positive rates this tool cares about.
+const bloomHashCount = 4
+
+// deduper reports whether an item has already been seen, recording it as
+// seen the first time it is checked. It backs the -u/--unique filter.
+type deduper interface {
+	seen(item string) bool
+}
+
+// newDeduper builds the deduper selected by --dedup. "map" (the default)
+// is exact but grows unbounded with the number of unique items; "bloom"
+// trades a small false-positive rate (a genuinely new item occasionally
+// gets dropped as a dupe) for memory that stays fixed regardless of how
+// many candidates stream through, which matters for multi-million-line
+// runs.
+func newDeduper(cfg *Config) deduper {
+	if cfg.Dedup == "bloom" {
+		size := cfg.DedupSize
+		if size <= 0 {
+			size = defaultBloomBits
+		}
+		return newBloomDeduper(uint64(size))
+	}
+	return newMapDeduper()
+}
+
+// mapDeduper is the exact, map-backed deduper used by default.
+type mapDeduper struct {
+	seenSet map[string]bool
+}
+
+func newMapDeduper() *mapDeduper {
+	return &mapDeduper{seenSet: make(map[string]bool)}
+}
+
+func (d *mapDeduper) seen(item string) bool {
+	if d.seenSet[item] {
+		return true
+	}
+	d.seenSet[item] = true
+	return false
+}
+
+// bloomDeduper is a fixed-size bloom filter: memory is bounded by size
+// regardless of stream length, at the cost of occasional false positives.
+type bloomDeduper struct {
+	bits []uint64
+	size uint64
+}
+
+func newBloomDeduper(size uint64) *bloomDeduper {
+	return &bloomDeduper{bits: make([]uint64, (size+63)/64), size: size}
+}
+
+func (d *bloomDeduper) seen(item string) bool {
+	h1, h2 := bloomHashes(item)
+
+	positions := make([]uint64, bloomHashCount)
+	allSet := true
+	for i := 0; i < bloomHashCount; i++ {
+		pos := (h1 + uint64(i)*h2) % d.size
+		positions[i] = pos
+		if d.bits[pos/64]&(1<<(pos%64)) == 0 {
+			allSet = false
+		}
+	}
+	if allSet {
+		return true
+	}
+
+	for _, pos := range positions {
+		d.bits[pos/64] |= 1 << (pos % 64)
+	}
+	return false
+}
+
+// bloomHashes derives two independent 64-bit hashes of item using FNV-1a,
+// which double hashing then combines into bloomHashCount bit positions
+// (Kirsch-Mitzenmacher).
+func bloomHashes(item string) (uint64, uint64) {
+	h1 := fnv.New64a()
+	h1.Write([]byte(item))
+
+	h2 := fnv.New64a()
+	h2.Write([]byte(item))
+	h2.Write([]byte{0})
+
+	return h1.Sum64(), h2.Sum64()
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestBloomDeduperSeen(t *testing.T) {
+	d := newBloomDeduper(10_000)
+
+	if d.seen("a.example.com") {
+		t.Fatal("first sighting of item reported as already seen")
+	}
+	if !d.seen("a.example.com") {
+		t.Fatal("second sighting of item not reported as seen")
+	}
+	if d.seen("b.example.com") {
+		t.Fatal("first sighting of a different item reported as already seen")
+	}
+}
+
+func TestMapDeduperSeen(t *testing.T) {
+	d := newMapDeduper()
+
+	if d.seen("x") {
+		t.Fatal("first sighting of item reported as already seen")
+	}
+	if !d.seen("x") {
+		t.Fatal("second sighting of item not reported as seen")
+	}
+}
+
+func TestNewDeduperSelectsStrategy(t *testing.T) {
+	if _, ok := newDeduper(&Config{Dedup: "bloom"}).(*bloomDeduper); !ok {
+		t.Error("newDeduper with Dedup=bloom did not return a *bloomDeduper")
+	}
+	if _, ok := newDeduper(&Config{Dedup: "map"}).(*mapDeduper); !ok {
+		t.Error("newDeduper with Dedup=map did not return a *mapDeduper")
+	}
+	if _, ok := newDeduper(&Config{}).(*mapDeduper); !ok {
+		t.Error("newDeduper with no Dedup set did not default to *mapDeduper")
+	}
+}
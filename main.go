@@ -2,6 +2,9 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -24,7 +27,30 @@ OPTIONS:
     -v, --verbose        Enable verbose output
     -h, --help           Show this help message
     --version            Show version information
-    -f, --format FORMAT  Output format: plain, json, csv (default: plain)
+    -f, --format FORMAT  Output format: plain, json, ndjson, csv (default: plain)
+    --pretty             Indent -f json array output
+    -s, --sources LIST   Comma-separated passive sources to query (crtsh,
+                          hackertarget, threatcrowd, otx, virustotal)
+    --config FILE        Config file with API keys for passive sources
+    -r, --resolve        Resolve candidates via DNS and keep only live hosts
+    --resolvers FILE     List of resolver IPs to use round-robin
+    --rate N             DNS queries per second when resolving (default: 50)
+    --retries N          Retry a failed lookup up to N times (default: 1)
+    --mode MODE          Generation mode: permute, wordlist (default: permute)
+                          -r/--resolve and -s/--sources are not supported
+                          with --mode wordlist
+    -w, --wordlist FILE  Wordlist file for --mode wordlist
+    --numbers RANGE      Numeric range, e.g. 0-9, inserted around labels
+                          that contain digits (--mode wordlist only)
+    --max N              Stop after N results (--mode wordlist only)
+    --dedup STRATEGY     Dedup strategy for -u/--unique: map, bloom
+                          (default: map)
+    --dedup-size N       Bloom filter size in bits (--dedup bloom)
+    --progress           Print periodic result counts to stderr
+    --base DOMAIN        Override the registrable base domain (skips
+                          public suffix list lookup entirely)
+    --psl-file FILE      Custom public suffix list for private suffixes
+                          not covered by the bundled list
 
 EXAMPLES:
     # Single subdomain
@@ -38,13 +64,36 @@ EXAMPLES:
     cat subdomains.txt | subcomb -o results.txt
 
     # Multiple formats
-    subcomb -f json sub.api.example.com
+    subcomb -f json --pretty sub.api.example.com
     subcomb -f csv -i input.txt -o output.csv
 
+    # Stream one JSON object per line for downstream tools (jq, ELK, ...)
+    subcomb -f ndjson -i subdomains.txt | jq .permutation
+
+    # Augment with passive sources
+    subcomb -s crtsh,hackertarget sub.api.example.com
+    subcomb -s virustotal --config subcomb.conf sub.api.example.com
+
+    # Resolve candidates and drop dead hosts
+    subcomb -r sub.api.example.com
+    subcomb -r --resolvers resolvers.txt --rate 200 -i subdomains.txt
+
+    # Wordlist-based permutations (altdns/dnsgen style)
+    subcomb --mode wordlist -w words.txt sub.api.example.com
+    subcomb --mode wordlist -w words.txt --numbers 0-9 --max 100000 api1.example.com
+
+    # Bounded-memory dedup for huge runs, with progress on stderr
+    subcomb --dedup bloom --dedup-size 50000000 --progress -i huge.txt -o out.txt
+
+    # Correct eTLD+1 handling for multi-label suffixes
+    subcomb x.y.s3.amazonaws.com
+    subcomb --base internal.corp sub.app.internal.corp
+
 FORMATS:
-    plain  - One subdomain per line (default)
-    json   - JSON array format
-    csv    - Comma-separated values with header
+    plain   - One subdomain per line (default)
+    json    - JSON array of {input, permutation, base, source} objects
+    ndjson  - One {input, permutation, base, source} object per line
+    csv     - Comma-separated values with header
 `
 )
 
@@ -56,10 +105,49 @@ type Config struct {
 	Format      string
 	ShowHelp    bool
 	ShowVersion bool
+	Sources     string
+	ConfigFile  string
+	Resolve     bool
+	Resolvers   string
+	Rate        int
+	Retries     int
+	Mode        string
+	Wordlist    string
+	Numbers     string
+	Max         int
+	Dedup       string
+	DedupSize   int
+	Progress    bool
+	Base        string
+	PSLFile     string
+	Pretty      bool
 }
 
 type SubdomainGenerator struct {
-	config *Config
+	config      *Config
+	baseDomains []string
+	processErr  error
+	pslMatcher  *pslMatcher
+}
+
+// Result is one generated candidate plus the metadata behind the stable
+// JSON/NDJSON schema: which input line produced it, its registrable base
+// domain, and the mechanism that produced it ("permute" or "passive").
+type Result struct {
+	Input       string
+	Permutation string
+	Base        string
+	Source      string
+}
+
+// resultJSON is the JSON wire shape for Result - a stable schema so
+// downstream tools (jq, resolvers, ELK-style pipelines) can rely on field
+// names instead of parsing bare strings.
+type resultJSON struct {
+	Input       string `json:"input"`
+	Permutation string `json:"permutation"`
+	Base        string `json:"base"`
+	Source      string `json:"source"`
 }
 
 // NewSubdomainGenerator creates a new generator with config
@@ -84,6 +172,25 @@ func ParseFlags() (*Config, []string) {
 	flag.BoolVar(&config.ShowVersion, "version", false, "Show version")
 	flag.StringVar(&config.Format, "f", "plain", "Output format")
 	flag.StringVar(&config.Format, "format", "plain", "Output format")
+	flag.BoolVar(&config.Pretty, "pretty", false, "Indent -f json array output")
+	flag.StringVar(&config.Sources, "s", "", "Passive sources")
+	flag.StringVar(&config.Sources, "sources", "", "Passive sources")
+	flag.StringVar(&config.ConfigFile, "config", "", "Passive source config file")
+	flag.BoolVar(&config.Resolve, "r", false, "Resolve candidates via DNS")
+	flag.BoolVar(&config.Resolve, "resolve", false, "Resolve candidates via DNS")
+	flag.StringVar(&config.Resolvers, "resolvers", "", "Resolver IPs file")
+	flag.IntVar(&config.Rate, "rate", 50, "DNS queries per second")
+	flag.IntVar(&config.Retries, "retries", 1, "DNS lookup retries")
+	flag.StringVar(&config.Mode, "mode", "permute", "Generation mode: permute, wordlist")
+	flag.StringVar(&config.Wordlist, "w", "", "Wordlist file")
+	flag.StringVar(&config.Wordlist, "wordlist", "", "Wordlist file")
+	flag.StringVar(&config.Numbers, "numbers", "", "Numeric range, e.g. 0-9")
+	flag.IntVar(&config.Max, "max", 0, "Stop after N results (0 = unlimited)")
+	flag.StringVar(&config.Dedup, "dedup", "map", "Dedup strategy: map, bloom")
+	flag.IntVar(&config.DedupSize, "dedup-size", defaultBloomBits, "Bloom filter size in bits (--dedup bloom)")
+	flag.BoolVar(&config.Progress, "progress", false, "Print periodic progress counts to stderr")
+	flag.StringVar(&config.Base, "base", "", "Override the registrable base domain")
+	flag.StringVar(&config.PSLFile, "psl-file", "", "Custom public suffix list file")
 
 	flag.Usage = func() {
 		fmt.Fprint(os.Stderr, usage)
@@ -119,13 +226,23 @@ func (sg *SubdomainGenerator) GeneratePermutations(subdomain string) []string {
 		return []string{subdomain}
 	}
 
-	// Extract domain and TLD (last two parts)
-	domain := parts[len(parts)-2]
-	tld := parts[len(parts)-1]
-	subdomainParts := parts[:len(parts)-2]
+	// Extract the registrable base domain (eTLD+1) so multi-label suffixes
+	// like co.uk or s3.amazonaws.com split correctly instead of treating
+	// the last two labels as domain+TLD.
+	baseDomain, err := sg.effectiveBaseDomain(subdomain)
+	if err != nil {
+		if sg.config.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: could not determine base domain for '%s': %v\n", subdomain, err)
+		}
+		return []string{}
+	}
+
+	var subdomainParts []string
+	if prefix := strings.TrimSuffix(subdomain, "."+baseDomain); prefix != subdomain && prefix != "" {
+		subdomainParts = strings.Split(prefix, ".")
+	}
 
 	var combinations []string
-	baseDomain := domain + "." + tld
 
 	// Add base domain
 	combinations = append(combinations, baseDomain)
@@ -197,41 +314,110 @@ func (sg *SubdomainGenerator) removeDuplicates(slice []string) []string {
 	return result
 }
 
-// ProcessInput reads from input source and processes subdomains
-func (sg *SubdomainGenerator) ProcessInput(reader io.Reader) ([]string, error) {
-	var allResults []string
-	scanner := bufio.NewScanner(reader)
+// ProcessInput reads from input source and streams results through the
+// returned channel as they are generated, deduping per item via the
+// deduper selected by --dedup. The channel is closed once input is
+// exhausted; call ProcessErr afterwards to check for a scan error, which
+// can't be returned synchronously once generation has started streaming.
+func (sg *SubdomainGenerator) ProcessInput(reader io.Reader) (<-chan Result, error) {
+	sourceNames := ParseSourceNames(sg.config.Sources)
+	var sourceConfig *SourceConfig
+	if len(sourceNames) > 0 {
+		if sg.config.ConfigFile != "" {
+			cfg, err := LoadSourceConfig(sg.config.ConfigFile)
+			if err != nil {
+				return nil, err
+			}
+			sourceConfig = cfg
+		} else {
+			sourceConfig = &SourceConfig{APIKeys: make(map[string]string)}
+		}
+	}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue // Skip empty lines and comments
+	out := make(chan Result)
+
+	go func() {
+		defer close(out)
+
+		var dedup deduper
+		if sg.config.Unique {
+			dedup = newDeduper(sg.config)
 		}
 
-		if sg.config.Verbose {
-			fmt.Fprintf(os.Stderr, "Processing: %s\n", line)
+		emitted := 0
+		emit := func(input, permutation, base, source string) {
+			if dedup != nil && dedup.seen(permutation) {
+				return
+			}
+			out <- Result{Input: input, Permutation: permutation, Base: base, Source: source}
+			emitted++
+			if sg.config.Progress && emitted%1000 == 0 {
+				fmt.Fprintf(os.Stderr, "progress: %d results\n", emitted)
+			}
 		}
 
-		results := sg.GeneratePermutations(line)
-		allResults = append(allResults, results...)
-	}
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue // Skip empty lines and comments
+			}
+
+			if sg.config.Verbose {
+				fmt.Fprintf(os.Stderr, "Processing: %s\n", line)
+			}
+
+			base := sg.baseDomain(line)
+
+			for _, permutation := range sg.GeneratePermutations(line) {
+				emit(line, permutation, base, "permute")
+			}
+
+			if base != "" && !sg.contains(sg.baseDomains, base) {
+				sg.baseDomains = append(sg.baseDomains, base)
+			}
+
+			if len(sourceNames) > 0 && base != "" {
+				if sg.config.Verbose {
+					fmt.Fprintf(os.Stderr, "Querying passive sources for: %s\n", base)
+				}
+				for _, host := range EnumeratePassive(base, sourceNames, sourceConfig, sg.config.Verbose) {
+					emit(line, host, base, "passive")
+				}
+			}
+		}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading input: %v", err)
-	}
+		if err := scanner.Err(); err != nil {
+			sg.processErr = fmt.Errorf("error reading input: %v", err)
+		}
 
-	if sg.config.Unique {
-		allResults = sg.removeDuplicates(allResults)
-	}
+		if sg.config.Progress {
+			fmt.Fprintf(os.Stderr, "progress: %d results (done)\n", emitted)
+		}
+	}()
+
+	return out, nil
+}
 
-	return allResults, nil
+// baseDomain extracts the registrable domain (eTLD+1) from a subdomain,
+// e.g. "sub.api.example.com" -> "example.com", or "" if it can't be
+// determined (see effectiveBaseDomain).
+func (sg *SubdomainGenerator) baseDomain(subdomain string) string {
+	base, err := sg.effectiveBaseDomain(subdomain)
+	if err != nil {
+		return ""
+	}
+	return base
 }
 
-// WriteOutput writes results to output destination
-func (sg *SubdomainGenerator) WriteOutput(writer io.Writer, results []string) error {
+// WriteOutput streams results to writer as they arrive from the channel,
+// in the requested format, and returns how many were written.
+func (sg *SubdomainGenerator) WriteOutput(writer io.Writer, results <-chan Result) (int, error) {
 	switch sg.config.Format {
 	case "json":
 		return sg.writeJSON(writer, results)
+	case "ndjson":
+		return sg.writeNDJSON(writer, results)
 	case "csv":
 		return sg.writeCSV(writer, results)
 	default:
@@ -239,40 +425,205 @@ func (sg *SubdomainGenerator) WriteOutput(writer io.Writer, results []string) er
 	}
 }
 
-// writePlain writes plain text output
-func (sg *SubdomainGenerator) writePlain(writer io.Writer, results []string) error {
-	for _, result := range results {
-		if _, err := fmt.Fprintln(writer, result); err != nil {
+// writePlain writes plain text output, one permutation per line.
+func (sg *SubdomainGenerator) writePlain(writer io.Writer, results <-chan Result) (int, error) {
+	count := 0
+	for result := range results {
+		if _, err := fmt.Fprintln(writer, result.Permutation); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// writeJSON streams a JSON array of the stable {input, permutation, base,
+// source} schema, encoding each result with encoding/json so names
+// containing quotes or backslashes come out correctly escaped instead of
+// corrupting the array, as naive "%s" quoting would. --pretty indents it.
+func (sg *SubdomainGenerator) writeJSON(writer io.Writer, results <-chan Result) (int, error) {
+	pretty := sg.config.Pretty
+
+	if pretty {
+		fmt.Fprint(writer, "[\n")
+	} else {
+		fmt.Fprint(writer, "[")
+	}
+
+	count := 0
+	for result := range results {
+		encoded, err := marshalResult(result, pretty)
+		if err != nil {
+			return count, err
+		}
+
+		if count > 0 {
+			if pretty {
+				fmt.Fprint(writer, ",\n")
+			} else {
+				fmt.Fprint(writer, ",")
+			}
+		}
+		if pretty {
+			fmt.Fprint(writer, "  ")
+		}
+		if _, err := writer.Write(encoded); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if pretty {
+		fmt.Fprint(writer, "\n]\n")
+	} else {
+		fmt.Fprintln(writer, "]")
+	}
+	return count, nil
+}
+
+// writeNDJSON streams one {input, permutation, base, source} object per
+// line, so downstream tools can consume subcomb's output incrementally.
+func (sg *SubdomainGenerator) writeNDJSON(writer io.Writer, results <-chan Result) (int, error) {
+	count := 0
+	for result := range results {
+		encoded, err := marshalResult(result, false)
+		if err != nil {
+			return count, err
+		}
+		if _, err := writer.Write(encoded); err != nil {
+			return count, err
+		}
+		if _, err := writer.Write([]byte("\n")); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// marshalResult encodes a Result using the stable resultJSON schema.
+func marshalResult(result Result, pretty bool) ([]byte, error) {
+	obj := resultJSON{
+		Input:       result.Input,
+		Permutation: result.Permutation,
+		Base:        result.Base,
+		Source:      result.Source,
+	}
+	if pretty {
+		return json.MarshalIndent(obj, "  ", "  ")
+	}
+	return json.Marshal(obj)
+}
+
+// writeCSV writes CSV output via encoding/csv so values containing commas
+// or quotes are escaped correctly.
+func (sg *SubdomainGenerator) writeCSV(writer io.Writer, results <-chan Result) (int, error) {
+	csvWriter := csv.NewWriter(writer)
+	defer csvWriter.Flush()
+
+	if err := csvWriter.Write([]string{"subdomain"}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for result := range results {
+		if err := csvWriter.Write([]string{result.Permutation}); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, csvWriter.Error()
+}
+
+// WriteResolvedOutput writes resolved hosts, including their DNS answers,
+// in the requested format.
+func (sg *SubdomainGenerator) WriteResolvedOutput(writer io.Writer, hosts []ResolvedHost) error {
+	switch sg.config.Format {
+	case "json":
+		return sg.writeResolvedJSON(writer, hosts)
+	case "csv":
+		return sg.writeResolvedCSV(writer, hosts)
+	default:
+		return sg.writeResolvedPlain(writer, hosts)
+	}
+}
+
+// writeResolvedPlain writes "host IP,IP,..." per line.
+func (sg *SubdomainGenerator) writeResolvedPlain(writer io.Writer, hosts []ResolvedHost) error {
+	for _, host := range hosts {
+		ips := append(append([]string{}, host.A...), host.AAAA...)
+		if _, err := fmt.Fprintf(writer, "%s %s\n", host.Name, strings.Join(ips, ",")); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// writeJSON writes JSON output
-func (sg *SubdomainGenerator) writeJSON(writer io.Writer, results []string) error {
+// writeResolvedJSON writes a JSON array of objects with name, a, aaaa and
+// cname fields.
+func (sg *SubdomainGenerator) writeResolvedJSON(writer io.Writer, hosts []ResolvedHost) error {
+	type resolvedJSON struct {
+		Name  string   `json:"name"`
+		A     []string `json:"a"`
+		AAAA  []string `json:"aaaa"`
+		CNAME string   `json:"cname,omitempty"`
+	}
+
 	fmt.Fprint(writer, "[")
-	for i, result := range results {
+	for i, host := range hosts {
 		if i > 0 {
 			fmt.Fprint(writer, ",")
 		}
-		fmt.Fprintf(writer, "\"%s\"", result)
+		encoded, err := json.Marshal(resolvedJSON{
+			Name:  host.Name,
+			A:     host.A,
+			AAAA:  host.AAAA,
+			CNAME: host.CNAME,
+		})
+		if err != nil {
+			return err
+		}
+		writer.Write(encoded)
 	}
 	fmt.Fprintln(writer, "]")
 	return nil
 }
 
-// writeCSV writes CSV output
-func (sg *SubdomainGenerator) writeCSV(writer io.Writer, results []string) error {
-	fmt.Fprintln(writer, "subdomain")
-	for _, result := range results {
-		fmt.Fprintln(writer, result)
+// writeResolvedCSV writes CSV output with extra ip/cname columns, via
+// encoding/csv so values containing commas or quotes are escaped
+// correctly.
+func (sg *SubdomainGenerator) writeResolvedCSV(writer io.Writer, hosts []ResolvedHost) error {
+	csvWriter := csv.NewWriter(writer)
+	defer csvWriter.Flush()
+
+	if err := csvWriter.Write([]string{"subdomain", "a", "aaaa", "cname"}); err != nil {
+		return err
 	}
-	return nil
+
+	for _, host := range hosts {
+		row := []string{
+			host.Name,
+			strings.Join(host.A, "|"),
+			strings.Join(host.AAAA, "|"),
+			host.CNAME,
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+	return csvWriter.Error()
 }
 
 // Run executes the main logic
 func (sg *SubdomainGenerator) Run(args []string) error {
+	if sg.config.PSLFile != "" {
+		matcher, err := loadPSLFile(sg.config.PSLFile)
+		if err != nil {
+			return err
+		}
+		sg.pslMatcher = matcher
+	}
+
 	var reader io.Reader
 	var writer io.Writer = os.Stdout
 
@@ -307,18 +658,228 @@ func (sg *SubdomainGenerator) Run(args []string) error {
 		writer = file
 	}
 
+	if sg.config.Mode == "wordlist" {
+		if sg.config.Resolve {
+			return fmt.Errorf("-r/--resolve is not supported with --mode wordlist")
+		}
+		if sg.config.Sources != "" {
+			return fmt.Errorf("-s/--sources is not supported with --mode wordlist")
+		}
+		return sg.RunWordlist(reader, writer)
+	}
+
 	// Process input
-	results, err := sg.ProcessInput(reader)
+	resultsChan, err := sg.ProcessInput(reader)
 	if err != nil {
 		return err
 	}
 
-	if sg.config.Verbose {
-		fmt.Fprintf(os.Stderr, "Generated %d results\n", len(results))
+	if sg.config.Resolve {
+		// ResolveHosts takes a []string, not a channel, so resolving
+		// still needs every candidate in memory up front - unlike
+		// plain/json/csv output, which streams from resultsChan directly.
+		results := drainChannel(resultsChan)
+		if sg.processErr != nil {
+			return sg.processErr
+		}
+
+		hosts := make([]string, len(results))
+		for i, result := range results {
+			hosts[i] = result.Permutation
+		}
+
+		resolved, err := sg.resolveResults(hosts)
+		if err != nil {
+			return err
+		}
+		return sg.WriteResolvedOutput(writer, resolved)
 	}
 
 	// Write output
-	return sg.WriteOutput(writer, results)
+	count, err := sg.WriteOutput(writer, resultsChan)
+	if err != nil {
+		return err
+	}
+	if sg.processErr != nil {
+		return sg.processErr
+	}
+
+	if sg.config.Verbose {
+		fmt.Fprintf(os.Stderr, "Generated %d results\n", count)
+	}
+
+	return nil
+}
+
+// drainChannel collects every item from ch into a slice.
+func drainChannel(ch <-chan Result) []Result {
+	var results []Result
+	for item := range ch {
+		results = append(results, item)
+	}
+	return results
+}
+
+// resolveResults runs the DNS resolution stage over results, using the
+// first base domain seen during ProcessInput for wildcard detection.
+func (sg *SubdomainGenerator) resolveResults(results []string) ([]ResolvedHost, error) {
+	var resolvers []string
+	if sg.config.Resolvers != "" {
+		r, err := LoadResolvers(sg.config.Resolvers)
+		if err != nil {
+			return nil, err
+		}
+		resolvers = r
+	}
+
+	var baseDomain string
+	if len(sg.baseDomains) > 0 {
+		baseDomain = sg.baseDomains[0]
+	}
+
+	if sg.config.Verbose {
+		fmt.Fprintf(os.Stderr, "Resolving %d candidates (rate %d/s)\n", len(results), sg.config.Rate)
+	}
+
+	return ResolveHosts(results, resolvers, sg.config.Rate, sg.config.Retries, baseDomain, sg.config.Verbose), nil
+}
+
+// RunWordlist handles --mode wordlist: it reads hosts from reader and
+// streams altdns-style mutations straight to writer as they are generated,
+// so multi-million-line wordlist runs stay within bounded memory. Capping
+// via --max cancels the generator goroutine rather than waiting for it to
+// run out of words on its own.
+func (sg *SubdomainGenerator) RunWordlist(reader io.Reader, writer io.Writer) error {
+	if sg.config.Wordlist == "" {
+		return fmt.Errorf("--mode wordlist requires -w/--wordlist FILE")
+	}
+
+	words, err := LoadWordlist(sg.config.Wordlist)
+	if err != nil {
+		return err
+	}
+
+	var numbers *NumberRange
+	if sg.config.Numbers != "" {
+		numbers, err = ParseNumberRange(sg.config.Numbers)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dedup := newDeduper(sg.config)
+	count := 0
+	first := true
+
+	pretty := sg.config.Pretty
+
+	switch sg.config.Format {
+	case "json":
+		if pretty {
+			fmt.Fprint(writer, "[\n")
+		} else {
+			fmt.Fprint(writer, "[")
+		}
+	case "csv":
+		fmt.Fprintln(writer, "subdomain")
+	}
+
+	emit := func(host, mutation string) error {
+		if sg.config.Unique && dedup.seen(mutation) {
+			return nil
+		}
+
+		switch sg.config.Format {
+		case "json":
+			encoded, err := marshalResult(Result{Input: host, Permutation: mutation, Source: "wordlist"}, pretty)
+			if err != nil {
+				return err
+			}
+			if !first {
+				if pretty {
+					fmt.Fprint(writer, ",\n")
+				} else {
+					fmt.Fprint(writer, ",")
+				}
+			}
+			if pretty {
+				fmt.Fprint(writer, "  ")
+			}
+			if _, err := writer.Write(encoded); err != nil {
+				return err
+			}
+		case "ndjson":
+			encoded, err := marshalResult(Result{Input: host, Permutation: mutation, Source: "wordlist"}, false)
+			if err != nil {
+				return err
+			}
+			if _, err := writer.Write(encoded); err != nil {
+				return err
+			}
+			if _, err := writer.Write([]byte("\n")); err != nil {
+				return err
+			}
+		case "csv":
+			if _, err := fmt.Fprintln(writer, mutation); err != nil {
+				return err
+			}
+		default:
+			if _, err := fmt.Fprintln(writer, mutation); err != nil {
+				return err
+			}
+		}
+
+		first = false
+		count++
+		return nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+outer:
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if sg.config.Verbose {
+			fmt.Fprintf(os.Stderr, "Generating wordlist mutations for: %s\n", line)
+		}
+
+		for mutation := range GenerateWordlistPermutations(ctx, line, words, numbers) {
+			if err := emit(line, mutation); err != nil {
+				return err
+			}
+			if sg.config.Progress && count > 0 && count%1000 == 0 {
+				fmt.Fprintf(os.Stderr, "progress: %d results\n", count)
+			}
+			if sg.config.Max > 0 && count >= sg.config.Max {
+				cancel()
+				break outer
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading input: %v", err)
+	}
+
+	if sg.config.Format == "json" {
+		if pretty {
+			fmt.Fprint(writer, "\n]\n")
+		} else {
+			fmt.Fprintln(writer, "]")
+		}
+	}
+
+	if sg.config.Verbose {
+		fmt.Fprintf(os.Stderr, "Generated %d results\n", count)
+	}
+
+	return nil
 }
 
 func main() {
@@ -340,4 +901,4 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
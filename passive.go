@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sourceTimeout bounds how long a single passive source is allowed to run
+// before it is abandoned. Individual sources never block the whole run.
+const sourceTimeout = 15 * time.Second
+
+// Source is implemented by anything that can turn a domain into a stream of
+// hostnames discovered out-of-band (passive DNS, certificate transparency,
+// etc). New sources are added by implementing this interface and registering
+// them in AllSources - the runner never needs to change.
+type Source interface {
+	Name() string
+	Enumerate(ctx context.Context, domain string) (<-chan string, error)
+}
+
+// SourceConfig holds per-source API keys loaded from --config.
+type SourceConfig struct {
+	APIKeys map[string]string
+}
+
+// LoadSourceConfig reads a "source: key" per line config file. This is
+// deliberately not YAML - subcomb avoids a YAML dependency to stay a single
+// static binary - so blank lines and lines starting with '#' are ignored,
+// and every other line must be "source: key"; anything else, including a
+// source name this build doesn't know about, is a hard error rather than a
+// silently dropped line.
+func LoadSourceConfig(path string) (*SourceConfig, error) {
+	cfg := &SourceConfig{APIKeys: make(map[string]string)}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening config file: %v", err)
+	}
+	defer file.Close()
+
+	known := AllSources(&SourceConfig{APIKeys: make(map[string]string)})
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid config line %q: expected \"source: key\"", line)
+		}
+
+		key := strings.TrimSpace(parts[0])
+		if _, ok := known[key]; !ok {
+			return nil, fmt.Errorf("invalid config line %q: unknown source %q", line, key)
+		}
+		value := strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+		cfg.APIKeys[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading config file: %v", err)
+	}
+
+	return cfg, nil
+}
+
+// AllSources returns every passive source subcomb knows about, keyed by the
+// name used with -s/--sources.
+func AllSources(cfg *SourceConfig) map[string]Source {
+	return map[string]Source{
+		"crtsh":        &crtShSource{},
+		"hackertarget": &hackerTargetSource{},
+		"threatcrowd":  &threatCrowdSource{},
+		"otx":          &alienVaultSource{},
+		"virustotal":   &virusTotalSource{apiKey: cfg.APIKeys["virustotal"]},
+	}
+}
+
+// ParseSourceNames splits a comma-separated -s/--sources value, trimming
+// whitespace and dropping empty entries.
+func ParseSourceNames(raw string) []string {
+	var names []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// EnumeratePassive queries the requested sources concurrently and returns
+// the merged, deduped set of hostnames they reported for domain. A failure
+// or timeout in one source never aborts the others - it is logged to
+// stderr (when verbose) and the source simply contributes nothing.
+func EnumeratePassive(domain string, names []string, cfg *SourceConfig, verbose bool) []string {
+	available := AllSources(cfg)
+
+	var wg sync.WaitGroup
+	found := make(chan string)
+
+	for _, name := range names {
+		source, ok := available[name]
+		if !ok {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Warning: unknown passive source '%s'\n", name)
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(source Source) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), sourceTimeout)
+			defer cancel()
+
+			results, err := source.Enumerate(ctx, domain)
+			if err != nil {
+				if verbose {
+					fmt.Fprintf(os.Stderr, "Warning: source '%s' failed: %v\n", source.Name(), err)
+				}
+				return
+			}
+
+			for host := range results {
+				found <- host
+			}
+		}(source)
+	}
+
+	go func() {
+		wg.Wait()
+		close(found)
+	}()
+
+	seen := make(map[string]bool)
+	var results []string
+	for host := range found {
+		host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+		if host != "" && !seen[host] {
+			seen[host] = true
+			results = append(results, host)
+		}
+	}
+
+	return results
+}
+
+// fetchJSON is a small helper shared by the HTTP-backed sources: it issues a
+// GET request and decodes the JSON response body into v.
+func fetchJSON(ctx context.Context, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: sourceTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// crtShSource queries crt.sh's JSON endpoint for certificates issued to the
+// domain and extracts the names they cover.
+type crtShSource struct{}
+
+func (s *crtShSource) Name() string { return "crtsh" }
+
+func (s *crtShSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	var entries []struct {
+		NameValue string `json:"name_value"`
+	}
+
+	rawURL := fmt.Sprintf("https://crt.sh/?q=%%25.%s&output=json", url.QueryEscape(domain))
+	if err := fetchJSON(ctx, rawURL, &entries); err != nil {
+		return nil, err
+	}
+
+	out := make(chan string, len(entries)*2)
+	go func() {
+		defer close(out)
+		for _, entry := range entries {
+			for _, name := range strings.Split(entry.NameValue, "\n") {
+				out <- strings.TrimPrefix(strings.TrimSpace(name), "*.")
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// hackerTargetSource queries HackerTarget's free hostsearch API.
+type hackerTargetSource struct{}
+
+func (s *hackerTargetSource) Name() string { return "hackertarget" }
+
+func (s *hackerTargetSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	rawURL := fmt.Sprintf("https://api.hackertarget.com/hostsearch/?q=%s", url.QueryEscape(domain))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: sourceTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if host := strings.SplitN(line, ",", 2)[0]; host != "" {
+				out <- host
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// threatCrowdSource queries ThreatCrowd's public domain report API.
+type threatCrowdSource struct{}
+
+func (s *threatCrowdSource) Name() string { return "threatcrowd" }
+
+func (s *threatCrowdSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	var report struct {
+		Subdomains []string `json:"subdomains"`
+	}
+
+	rawURL := fmt.Sprintf("https://www.threatcrowd.org/searchApi/v2/domain/report/?domain=%s", url.QueryEscape(domain))
+	if err := fetchJSON(ctx, rawURL, &report); err != nil {
+		return nil, err
+	}
+
+	out := make(chan string, len(report.Subdomains))
+	go func() {
+		defer close(out)
+		for _, host := range report.Subdomains {
+			out <- host
+		}
+	}()
+
+	return out, nil
+}
+
+// alienVaultSource queries AlienVault OTX's passive DNS API.
+type alienVaultSource struct{}
+
+func (s *alienVaultSource) Name() string { return "otx" }
+
+func (s *alienVaultSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	var response struct {
+		PassiveDNS []struct {
+			Hostname string `json:"hostname"`
+		} `json:"passive_dns"`
+	}
+
+	rawURL := fmt.Sprintf("https://otx.alienvault.com/api/v1/indicators/domain/%s/passive_dns", url.QueryEscape(domain))
+	if err := fetchJSON(ctx, rawURL, &response); err != nil {
+		return nil, err
+	}
+
+	out := make(chan string, len(response.PassiveDNS))
+	go func() {
+		defer close(out)
+		for _, record := range response.PassiveDNS {
+			out <- record.Hostname
+		}
+	}()
+
+	return out, nil
+}
+
+// virusTotalSource queries VirusTotal's domain report API. It requires an
+// API key, supplied via --config.
+type virusTotalSource struct {
+	apiKey string
+}
+
+func (s *virusTotalSource) Name() string { return "virustotal" }
+
+func (s *virusTotalSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	if s.apiKey == "" {
+		return nil, fmt.Errorf("no API key configured for virustotal")
+	}
+
+	var response struct {
+		Subdomains []string `json:"subdomains"`
+	}
+
+	rawURL := fmt.Sprintf("https://www.virustotal.com/vtapi/v2/domain/report?apikey=%s&domain=%s",
+		url.QueryEscape(s.apiKey), url.QueryEscape(domain))
+	if err := fetchJSON(ctx, rawURL, &response); err != nil {
+		return nil, err
+	}
+
+	out := make(chan string, len(response.Subdomains))
+	go func() {
+		defer close(out)
+		for _, host := range response.Subdomains {
+			out <- host
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// pslMatcher is a minimal public-suffix-list matcher built from a custom
+// --psl-file, for private suffixes (internal TLDs, on-prem registrars)
+// that will never appear in the list bundled with
+// golang.org/x/net/publicsuffix. It follows the same rule types as the
+// real PSL format: plain rules, "*." wildcard rules, and "!" exceptions.
+type pslMatcher struct {
+	exact      map[string]bool
+	wildcards  map[string]bool
+	exceptions map[string]bool
+}
+
+// loadPSLFile reads a public-suffix-list-formatted file, skipping blank
+// lines and "//" comments.
+func loadPSLFile(path string) (*pslMatcher, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening psl file: %v", err)
+	}
+	defer file.Close()
+
+	m := &pslMatcher{
+		exact:      make(map[string]bool),
+		wildcards:  make(map[string]bool),
+		exceptions: make(map[string]bool),
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "!"):
+			m.exceptions[strings.TrimPrefix(line, "!")] = true
+		case strings.HasPrefix(line, "*."):
+			m.wildcards[strings.TrimPrefix(line, "*.")] = true
+		default:
+			m.exact[line] = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading psl file: %v", err)
+	}
+
+	return m, nil
+}
+
+// registrableDomain returns the eTLD+1 for subdomain using this custom
+// suffix list: exception rules win over wildcard rules, which win over
+// exact rules, mirroring the public suffix list algorithm. A subdomain
+// that matches nothing falls back to treating its last label as the
+// suffix.
+func (m *pslMatcher) registrableDomain(subdomain string) (string, error) {
+	labels := strings.Split(subdomain, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("%q has no suffix to strip", subdomain)
+	}
+
+	for i := 0; i < len(labels)-1; i++ {
+		candidate := strings.Join(labels[i:], ".")
+		if m.exceptions[candidate] {
+			return candidate, nil
+		}
+	}
+
+	for i := 1; i < len(labels); i++ {
+		candidate := strings.Join(labels[i:], ".")
+		if m.wildcards[candidate] {
+			// labels[i-1:] is the public suffix itself (one label
+			// prepended to the wildcard base); the registrable domain
+			// needs one more label of headroom beyond that.
+			if i-2 < 0 {
+				return "", fmt.Errorf("%q is a suffix, not a registrable domain", subdomain)
+			}
+			return strings.Join(labels[i-2:], "."), nil
+		}
+	}
+
+	for i := 1; i < len(labels); i++ {
+		candidate := strings.Join(labels[i:], ".")
+		if m.exact[candidate] {
+			return strings.Join(labels[i-1:], "."), nil
+		}
+	}
+
+	return strings.Join(labels[len(labels)-2:], "."), nil
+}
+
+// effectiveBaseDomain returns the eTLD+1 registrable domain for subdomain.
+// --base always wins when set; otherwise a loaded --psl-file takes
+// priority over the bundled public suffix list, so private suffixes that
+// aren't in the PSL (internal TLDs, on-prem registrars) can still be
+// handled correctly.
+func (sg *SubdomainGenerator) effectiveBaseDomain(subdomain string) (string, error) {
+	subdomain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(subdomain)), ".")
+
+	if sg.config.Base != "" {
+		return sg.config.Base, nil
+	}
+
+	if sg.pslMatcher != nil {
+		return sg.pslMatcher.registrableDomain(subdomain)
+	}
+
+	return publicsuffix.EffectiveTLDPlusOne(subdomain)
+}
@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestPslMatcherRegistrableDomain(t *testing.T) {
+	m := &pslMatcher{
+		exact:      map[string]bool{"example.com": true},
+		wildcards:  map[string]bool{"kawasaki.jp": true, "ck": true},
+		exceptions: map[string]bool{"city.kawasaki.jp": true},
+	}
+
+	tests := []struct {
+		name    string
+		host    string
+		want    string
+		wantErr bool
+	}{
+		{name: "exact rule", host: "sub.example.com", want: "sub.example.com"},
+		{name: "wildcard rule multi-label", host: "a.b.foo.kawasaki.jp", want: "b.foo.kawasaki.jp"},
+		{name: "wildcard rule short form", host: "a.b.foo.ck", want: "b.foo.ck"},
+		{name: "wildcard rule at registrable boundary", host: "b.foo.ck", want: "b.foo.ck"},
+		{name: "wildcard suffix alone is not registrable", host: "foo.kawasaki.jp", wantErr: true},
+		{name: "wildcard suffix alone, short form", host: "foo.ck", wantErr: true},
+		{name: "exception rule wins over wildcard", host: "sub.city.kawasaki.jp", want: "city.kawasaki.jp"},
+		{name: "default rule, no matching suffix", host: "sub.unknown.tld", want: "unknown.tld"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.registrableDomain(tt.host)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("registrableDomain(%q) = %q, want error", tt.host, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("registrableDomain(%q) returned unexpected error: %v", tt.host, err)
+			}
+			if got != tt.want {
+				t.Errorf("registrableDomain(%q) = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dnsTimeout bounds a single lookup attempt (one try, before retries).
+const dnsTimeout = 5 * time.Second
+
+// ResolvedHost is a candidate subdomain together with the DNS answers
+// observed for it. CNAME is empty when the name has no CNAME record.
+type ResolvedHost struct {
+	Name  string
+	A     []string
+	AAAA  []string
+	CNAME string
+}
+
+// hasAnswer reports whether the host resolved to anything at all.
+func (r ResolvedHost) hasAnswer() bool {
+	return len(r.A) > 0 || len(r.AAAA) > 0 || r.CNAME != ""
+}
+
+// LoadResolvers reads one resolver IP per line from file, skipping blank
+// lines and comments.
+func LoadResolvers(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening resolvers file: %v", err)
+	}
+	defer file.Close()
+
+	var resolvers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		resolvers = append(resolvers, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading resolvers file: %v", err)
+	}
+
+	if len(resolvers) == 0 {
+		return nil, fmt.Errorf("resolvers file %s contains no resolver addresses", path)
+	}
+
+	return resolvers, nil
+}
+
+// resolverPool hands out resolvers round-robin, optionally pinning lookups
+// to specific servers instead of the system resolver config.
+type resolverPool struct {
+	resolvers []*net.Resolver
+	next      uint32
+	mu        sync.Mutex
+}
+
+// newResolverPool builds a pool from a list of "ip" or "ip:port" addresses.
+// An empty list falls back to a single pool entry backed by the system
+// resolver.
+func newResolverPool(servers []string) *resolverPool {
+	if len(servers) == 0 {
+		return &resolverPool{resolvers: []*net.Resolver{net.DefaultResolver}}
+	}
+
+	pool := &resolverPool{}
+	for _, server := range servers {
+		server := server
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		pool.resolvers = append(pool.resolvers, &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				dialer := net.Dialer{Timeout: dnsTimeout}
+				return dialer.DialContext(ctx, network, server)
+			},
+		})
+	}
+	return pool
+}
+
+func (p *resolverPool) get() *net.Resolver {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r := p.resolvers[p.next%uint32(len(p.resolvers))]
+	p.next++
+	return r
+}
+
+// tokenBucket is a minimal queries-per-second limiter built on a ticker so
+// the resolve stage never exceeds --rate, regardless of worker count.
+type tokenBucket struct {
+	tokens chan struct{}
+	stop   chan struct{}
+}
+
+func newTokenBucket(rate int) *tokenBucket {
+	if rate <= 0 {
+		rate = 100
+	}
+
+	tb := &tokenBucket{
+		tokens: make(chan struct{}, rate),
+		stop:   make(chan struct{}),
+	}
+
+	interval := time.Second / time.Duration(rate)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				select {
+				case tb.tokens <- struct{}{}:
+				default:
+				}
+			case <-tb.stop:
+				return
+			}
+		}
+	}()
+
+	return tb
+}
+
+func (tb *tokenBucket) take() {
+	<-tb.tokens
+}
+
+func (tb *tokenBucket) close() {
+	close(tb.stop)
+}
+
+// resolveOnce performs a single A/AAAA/CNAME lookup attempt for name.
+func resolveOnce(ctx context.Context, resolver *net.Resolver, name string) ResolvedHost {
+	result := ResolvedHost{Name: name}
+
+	ctx, cancel := context.WithTimeout(ctx, dnsTimeout)
+	defer cancel()
+
+	if ips, err := resolver.LookupIP(ctx, "ip4", name); err == nil {
+		for _, ip := range ips {
+			result.A = append(result.A, ip.String())
+		}
+	}
+
+	if ips, err := resolver.LookupIP(ctx, "ip6", name); err == nil {
+		for _, ip := range ips {
+			result.AAAA = append(result.AAAA, ip.String())
+		}
+	}
+
+	if cname, err := resolver.LookupCNAME(ctx, name); err == nil {
+		cname = strings.TrimSuffix(cname, ".")
+		if cname != "" && cname != strings.TrimSuffix(name, ".") {
+			result.CNAME = cname
+		}
+	}
+
+	return result
+}
+
+// resolveWithRetries retries a lookup up to retries times when it comes
+// back with no answer at all (transient resolver failures are common under
+// high query rates). bucket is drawn from before every attempt, not just
+// the first, so retries stay subject to --rate instead of bypassing it.
+func resolveWithRetries(ctx context.Context, resolver *net.Resolver, name string, retries int, bucket *tokenBucket) ResolvedHost {
+	var result ResolvedHost
+	for attempt := 0; attempt <= retries; attempt++ {
+		bucket.take()
+		result = resolveOnce(ctx, resolver, name)
+		if result.hasAnswer() {
+			return result
+		}
+	}
+	return result
+}
+
+// DetectWildcard probes baseDomain with a random nonexistent label and
+// returns the set of IPs it resolves to. An empty, non-nil-checked return
+// means the domain does not wildcard.
+func DetectWildcard(pool *resolverPool, baseDomain string) map[string]bool {
+	probe := fmt.Sprintf("subcomb-wildcard-check-%d.%s", rand.Int63(), baseDomain)
+	result := resolveOnce(context.Background(), pool.get(), probe)
+
+	ipSet := make(map[string]bool)
+	for _, ip := range result.A {
+		ipSet[ip] = true
+	}
+	for _, ip := range result.AAAA {
+		ipSet[ip] = true
+	}
+	return ipSet
+}
+
+// isWildcardAnswer reports whether every IP a host resolved to is also
+// part of the wildcard IP set, meaning the answer tells us nothing about
+// the candidate specifically.
+func isWildcardAnswer(result ResolvedHost, wildcardIPs map[string]bool) bool {
+	if len(wildcardIPs) == 0 || !result.hasAnswer() {
+		return false
+	}
+
+	for _, ip := range result.A {
+		if !wildcardIPs[ip] {
+			return false
+		}
+	}
+	for _, ip := range result.AAAA {
+		if !wildcardIPs[ip] {
+			return false
+		}
+	}
+	return len(result.A) > 0 || len(result.AAAA) > 0
+}
+
+// ResolveHosts resolves hosts concurrently through a worker pool, rate
+// limited to rate queries/second, retrying failed lookups up to retries
+// times, and drops any candidate whose answers are entirely covered by the
+// wildcard IP set observed for baseDomain. Results are returned in the
+// order hosts that actually resolved were found.
+func ResolveHosts(hosts []string, resolvers []string, rate int, retries int, baseDomain string, verbose bool) []ResolvedHost {
+	pool := newResolverPool(resolvers)
+
+	var wildcardIPs map[string]bool
+	if baseDomain != "" {
+		wildcardIPs = DetectWildcard(pool, baseDomain)
+		if verbose && len(wildcardIPs) > 0 {
+			fmt.Fprintf(os.Stderr, "Detected wildcard DNS for %s, filtering matching answers\n", baseDomain)
+		}
+	}
+
+	const workerCount = 20
+	jobs := make(chan string)
+	results := make(chan ResolvedHost)
+	bucket := newTokenBucket(rate)
+	defer bucket.close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for host := range jobs {
+				result := resolveWithRetries(context.Background(), pool.get(), host, retries, bucket)
+				if result.hasAnswer() && !isWildcardAnswer(result, wildcardIPs) {
+					results <- result
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for _, host := range hosts {
+			jobs <- host
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var resolved []ResolvedHost
+	for result := range results {
+		resolved = append(resolved, result)
+	}
+
+	return resolved
+}
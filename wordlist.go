@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NumberRange is an inclusive [Start, End] range parsed from --numbers,
+// used to mutate labels that already contain digits (e.g. api1 -> api0..api9).
+type NumberRange struct {
+	Start int
+	End   int
+}
+
+// ParseNumberRange parses a "--numbers" value like "0-9" into a NumberRange.
+func ParseNumberRange(raw string) (*NumberRange, error) {
+	parts := strings.SplitN(raw, "-", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid numbers range %q, expected format START-END", raw)
+	}
+
+	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid numbers range %q: %v", raw, err)
+	}
+
+	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid numbers range %q: %v", raw, err)
+	}
+
+	if end < start {
+		return nil, fmt.Errorf("invalid numbers range %q: end is before start", raw)
+	}
+
+	return &NumberRange{Start: start, End: end}, nil
+}
+
+// LoadWordlist reads one word per line from file, skipping blank lines and
+// comments, the same convention used by LoadResolvers.
+func LoadWordlist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening wordlist file: %v", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading wordlist file: %v", err)
+	}
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf("wordlist file %s contains no words", path)
+	}
+
+	return words, nil
+}
+
+// hasDigit reports whether label contains at least one digit.
+func hasDigit(label string) bool {
+	for _, r := range label {
+		if r >= '0' && r <= '9' {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateWordlistPermutations streams altdns/dnsgen-style mutations of host
+// built from words and, if numbers is non-nil, numeric ranges inserted
+// around labels that already contain digits. Results are sent to the
+// returned channel as they are produced rather than collected first, so
+// callers can cap a run (e.g. via --max) by cancelling ctx - the producer
+// goroutine observes cancellation instead of blocking forever on a send
+// nobody is reading anymore. The channel is closed when generation
+// finishes or ctx is done.
+func GenerateWordlistPermutations(ctx context.Context, host string, words []string, numbers *NumberRange) <-chan string {
+	out := make(chan string)
+
+	go func() {
+		defer close(out)
+
+		send := func(s string) bool {
+			select {
+			case out <- s:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+		labels := strings.Split(host, ".")
+		separators := []string{"-", "_", ""}
+
+		for _, word := range words {
+			// (a) insert the word as a new leftmost label.
+			if !send(strings.Join(append([]string{word}, labels...), ".")) {
+				return
+			}
+
+			for i, label := range labels {
+				// (b) prepend/append the word to this label.
+				for _, sep := range separators {
+					mutated := append([]string(nil), labels...)
+					mutated[i] = label + sep + word
+					if !send(strings.Join(mutated, ".")) {
+						return
+					}
+
+					mutated = append([]string(nil), labels...)
+					mutated[i] = word + sep + label
+					if !send(strings.Join(mutated, ".")) {
+						return
+					}
+				}
+
+				// (c) substitute this label with the word entirely.
+				substituted := append([]string(nil), labels...)
+				substituted[i] = word
+				if !send(strings.Join(substituted, ".")) {
+					return
+				}
+			}
+		}
+
+		if numbers == nil {
+			return
+		}
+
+		for i, label := range labels {
+			if !hasDigit(label) {
+				continue
+			}
+
+			for n := numbers.Start; n <= numbers.End; n++ {
+				num := strconv.Itoa(n)
+				for _, sep := range separators {
+					mutated := append([]string(nil), labels...)
+					mutated[i] = label + sep + num
+					if !send(strings.Join(mutated, ".")) {
+						return
+					}
+
+					mutated = append([]string(nil), labels...)
+					mutated[i] = num + sep + label
+					if !send(strings.Join(mutated, ".")) {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func collectPermutations(t *testing.T, host string, words []string, numbers *NumberRange) []string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var out []string
+	for mutation := range GenerateWordlistPermutations(ctx, host, words, numbers) {
+		out = append(out, mutation)
+	}
+	return out
+}
+
+func TestGenerateWordlistPermutations(t *testing.T) {
+	results := collectPermutations(t, "sub.example.com", []string{"api"}, nil)
+
+	want := []string{
+		"api.sub.example.com", // (a) new leftmost label
+		"sub-api.example.com", // (b) append to "sub"
+		"api-sub.example.com", // (b) prepend to "sub"
+		"api.example.com",     // (c) substitute "sub"
+	}
+	for _, w := range want {
+		found := false
+		for _, got := range results {
+			if got == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected mutation %q not found in results %v", w, results)
+		}
+	}
+}
+
+func TestGenerateWordlistPermutationsNumbers(t *testing.T) {
+	numbers := &NumberRange{Start: 0, End: 1}
+	results := collectPermutations(t, "api1.example.com", nil, numbers)
+
+	want := []string{"api1-0.example.com", "0-api1.example.com", "api1-1.example.com"}
+	for _, w := range want {
+		found := false
+		for _, got := range results {
+			if got == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected mutation %q not found in results %v", w, results)
+		}
+	}
+}
+
+func TestGenerateWordlistPermutationsStopsOnCancel(t *testing.T) {
+	words := make([]string, 10_000)
+	for i := range words {
+		words[i] = "word"
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := GenerateWordlistPermutations(ctx, "sub.example.com", words, nil)
+
+	const cap = 3
+	for i := 0; i < cap; i++ {
+		<-out
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range out {
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("generator goroutine kept sending after ctx was cancelled instead of stopping")
+	}
+}